@@ -0,0 +1,411 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cloudformation wraps the AWS CloudFormation API with the conveniences the rest of
+// Copilot needs on top of the raw SDK client: typed stack descriptions, waiters, and template
+// metadata parsing.
+package cloudformation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+)
+
+// deployCapabilities are requested on every stack Copilot creates or updates: IAM resources
+// with custom names and macro-expanded templates (nested stacks, SAM transforms) are common
+// across Copilot's own templates and addons.
+var deployCapabilities = aws.StringSlice([]string{
+	sdkcloudformation.CapabilityCapabilityNamedIam,
+	sdkcloudformation.CapabilityCapabilityAutoExpand,
+})
+
+// Stack holds the configuration needed to create or update a CloudFormation stack.
+type Stack struct {
+	Name     string
+	Template string
+
+	// TemplateURL, when set, is sent to CloudFormation instead of Template: it points at a
+	// template body already hosted in S3, which is required once the rendered template exceeds
+	// CloudFormation's inline TemplateBody size limit.
+	TemplateURL string
+
+	Parameters []*sdkcloudformation.Parameter
+	Tags       []*sdkcloudformation.Tag
+	RoleARN    *string
+}
+
+// NewStack returns a Stack that deploys template inline as name.
+func NewStack(name, template string) *Stack {
+	return &Stack{
+		Name:     name,
+		Template: template,
+	}
+}
+
+// StackDescription is a CloudFormation stack's current state.
+type StackDescription struct {
+	StackID     *string
+	StackName   *string
+	StackStatus *string
+	Outputs     []*sdkcloudformation.Output
+	Tags        []*sdkcloudformation.Tag
+}
+
+// StackEvent is a single CloudFormation stack event.
+type StackEvent = sdkcloudformation.StackEvent
+
+// ChangeSetDescription is a CloudFormation change set's current state.
+type ChangeSetDescription struct {
+	ChangeSetID  string
+	CreationTime *time.Time
+	Changes      []*sdkcloudformation.Change
+}
+
+// StackStatus is one of the CloudFormation StackStatus_* values.
+type StackStatus string
+
+// Failure reports whether s represents a stack that didn't end up in a usable state.
+func (s StackStatus) Failure() bool {
+	return strings.HasSuffix(string(s), "_FAILED") || strings.Contains(string(s), "ROLLBACK")
+}
+
+// ErrStackAlreadyExists is returned by Create when a stack with the same name already exists.
+type ErrStackAlreadyExists struct {
+	StackName string
+	Stack     *StackDescription
+}
+
+func (e *ErrStackAlreadyExists) Error() string {
+	return fmt.Sprintf("stack %s already exists", e.StackName)
+}
+
+func isAlreadyExistsErr(err error) bool {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return strings.Contains(aerr.Message(), "already exists")
+	}
+	return false
+}
+
+// CloudFormation wraps an AWS CloudFormation client with Copilot-specific conveniences.
+type CloudFormation struct {
+	client cloudformationiface.CloudFormationAPI
+}
+
+// New returns a CloudFormation configured against sess.
+func New(sess *session.Session) CloudFormation {
+	return CloudFormation{
+		client: sdkcloudformation.New(sess),
+	}
+}
+
+// DescribeStackEvents vends the raw SDK call through so callers that need pagination control
+// (e.g. for failure classification) aren't limited to the convenience methods below.
+func (c CloudFormation) DescribeStackEvents(in *sdkcloudformation.DescribeStackEventsInput) (*sdkcloudformation.DescribeStackEventsOutput, error) {
+	return c.client.DescribeStackEvents(in)
+}
+
+// DescribeStackResources vends the raw SDK call through, as DescribeStackEvents does above.
+func (c CloudFormation) DescribeStackResources(in *sdkcloudformation.DescribeStackResourcesInput) (*sdkcloudformation.DescribeStackResourcesOutput, error) {
+	return c.client.DescribeStackResources(in)
+}
+
+func stackInput(stack *Stack) (templateBody, templateURL *string) {
+	if stack.TemplateURL != "" {
+		return nil, aws.String(stack.TemplateURL)
+	}
+	return aws.String(stack.Template), nil
+}
+
+// Create starts creating stack and returns its stack ID without waiting for the operation to
+// finish; use WaitForCreate or CreateAndWait to block until it completes.
+func (c CloudFormation) Create(stack *Stack) (string, error) {
+	templateBody, templateURL := stackInput(stack)
+	out, err := c.client.CreateStack(&sdkcloudformation.CreateStackInput{
+		StackName:    aws.String(stack.Name),
+		TemplateBody: templateBody,
+		TemplateURL:  templateURL,
+		Parameters:   stack.Parameters,
+		Tags:         stack.Tags,
+		RoleARN:      stack.RoleARN,
+		Capabilities: deployCapabilities,
+	})
+	if err != nil {
+		if isAlreadyExistsErr(err) {
+			desc, _ := c.Describe(stack.Name)
+			return "", &ErrStackAlreadyExists{StackName: stack.Name, Stack: desc}
+		}
+		return "", fmt.Errorf("create stack %s: %w", stack.Name, err)
+	}
+	return aws.StringValue(out.StackId), nil
+}
+
+// CreateAndWait calls Create and blocks until the stack finishes creating.
+func (c CloudFormation) CreateAndWait(stack *Stack) error {
+	if _, err := c.Create(stack); err != nil {
+		return err
+	}
+	return c.WaitForCreate(context.Background(), stack.Name)
+}
+
+// WaitForCreate blocks until stackName finishes creating or ctx is canceled.
+func (c CloudFormation) WaitForCreate(ctx context.Context, stackName string) error {
+	return c.client.WaitUntilStackCreateCompleteWithContext(ctx, &sdkcloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+}
+
+// CreateChangeSet creates a change set of changeSetType (one of the sdk's ChangeSetType_*
+// values) against stack and returns the change set's ID.
+func (c CloudFormation) CreateChangeSet(stack *Stack, changeSetType string) (string, error) {
+	templateBody, templateURL := stackInput(stack)
+	out, err := c.client.CreateChangeSet(&sdkcloudformation.CreateChangeSetInput{
+		StackName:     aws.String(stack.Name),
+		ChangeSetName: aws.String(fmt.Sprintf("%s-%d", stack.Name, time.Now().UnixNano())),
+		ChangeSetType: aws.String(changeSetType),
+		TemplateBody:  templateBody,
+		TemplateURL:   templateURL,
+		Parameters:    stack.Parameters,
+		Tags:          stack.Tags,
+		RoleARN:       stack.RoleARN,
+		Capabilities:  deployCapabilities,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create change set for stack %s: %w", stack.Name, err)
+	}
+	return aws.StringValue(out.Id), nil
+}
+
+// Update starts updating stack without waiting for the operation to finish; use WaitForUpdate
+// or UpdateAndWait to block until it completes.
+func (c CloudFormation) Update(stack *Stack) error {
+	templateBody, templateURL := stackInput(stack)
+	_, err := c.client.UpdateStack(&sdkcloudformation.UpdateStackInput{
+		StackName:    aws.String(stack.Name),
+		TemplateBody: templateBody,
+		TemplateURL:  templateURL,
+		Parameters:   stack.Parameters,
+		Tags:         stack.Tags,
+		RoleARN:      stack.RoleARN,
+		Capabilities: deployCapabilities,
+	})
+	if err != nil {
+		return fmt.Errorf("update stack %s: %w", stack.Name, err)
+	}
+	return nil
+}
+
+// UpdateAndWait calls Update and blocks until the stack finishes updating.
+func (c CloudFormation) UpdateAndWait(stack *Stack) error {
+	if err := c.Update(stack); err != nil {
+		return err
+	}
+	return c.WaitForUpdate(context.Background(), stack.Name)
+}
+
+// WaitForUpdate blocks until stackName finishes updating or ctx is canceled.
+func (c CloudFormation) WaitForUpdate(ctx context.Context, stackName string) error {
+	return c.client.WaitUntilStackUpdateCompleteWithContext(ctx, &sdkcloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+}
+
+// Delete starts deleting stackName without waiting for the operation to finish.
+func (c CloudFormation) Delete(stackName string) error {
+	if _, err := c.client.DeleteStack(&sdkcloudformation.DeleteStackInput{StackName: aws.String(stackName)}); err != nil {
+		return fmt.Errorf("delete stack %s: %w", stackName, err)
+	}
+	return nil
+}
+
+// DeleteAndWait calls Delete and blocks until the stack finishes deleting.
+func (c CloudFormation) DeleteAndWait(stackName string) error {
+	if err := c.Delete(stackName); err != nil {
+		return err
+	}
+	return c.client.WaitUntilStackDeleteComplete(&sdkcloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+}
+
+// DeleteAndWaitWithRoleARN deletes stackName using roleARN and blocks until it finishes.
+func (c CloudFormation) DeleteAndWaitWithRoleARN(stackName, roleARN string) error {
+	_, err := c.client.DeleteStack(&sdkcloudformation.DeleteStackInput{
+		StackName: aws.String(stackName),
+		RoleARN:   aws.String(roleARN),
+	})
+	if err != nil {
+		return fmt.Errorf("delete stack %s: %w", stackName, err)
+	}
+	return c.client.WaitUntilStackDeleteComplete(&sdkcloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+}
+
+// Describe returns stackName's current state.
+func (c CloudFormation) Describe(stackName string) (*StackDescription, error) {
+	out, err := c.client.DescribeStacks(&sdkcloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return nil, fmt.Errorf("describe stack %s: %w", stackName, err)
+	}
+	if len(out.Stacks) == 0 {
+		return nil, fmt.Errorf("stack %s not found", stackName)
+	}
+	return toStackDescription(out.Stacks[0]), nil
+}
+
+// DescribeChangeSet returns the current state of changeSetID on stackName.
+func (c CloudFormation) DescribeChangeSet(changeSetID, stackName string) (*ChangeSetDescription, error) {
+	out, err := c.client.DescribeChangeSet(&sdkcloudformation.DescribeChangeSetInput{
+		ChangeSetName: aws.String(changeSetID),
+		StackName:     aws.String(stackName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe change set %s for stack %s: %w", changeSetID, stackName, err)
+	}
+	return &ChangeSetDescription{
+		ChangeSetID:  aws.StringValue(out.ChangeSetId),
+		CreationTime: out.CreationTime,
+		Changes:      out.Changes,
+	}, nil
+}
+
+// TemplateBody returns the template currently deployed to stackName.
+func (c CloudFormation) TemplateBody(stackName string) (string, error) {
+	out, err := c.client.GetTemplate(&sdkcloudformation.GetTemplateInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return "", fmt.Errorf("get template for stack %s: %w", stackName, err)
+	}
+	return aws.StringValue(out.TemplateBody), nil
+}
+
+// TemplateBodyFromChangeSet returns the template that changeSetID would deploy to stackName.
+func (c CloudFormation) TemplateBodyFromChangeSet(changeSetID, stackName string) (string, error) {
+	out, err := c.client.GetTemplate(&sdkcloudformation.GetTemplateInput{
+		StackName:     aws.String(stackName),
+		ChangeSetName: aws.String(changeSetID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("get template for change set %s on stack %s: %w", changeSetID, stackName, err)
+	}
+	return aws.StringValue(out.TemplateBody), nil
+}
+
+// Events returns every event recorded against stackName, oldest first.
+func (c CloudFormation) Events(stackName string) ([]StackEvent, error) {
+	var events []StackEvent
+	var nextToken *string
+	for {
+		out, err := c.client.DescribeStackEvents(&sdkcloudformation.DescribeStackEventsInput{
+			StackName: aws.String(stackName),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe stack events for %s: %w", stackName, err)
+		}
+		for _, e := range out.StackEvents {
+			events = append(events, *e)
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	// DescribeStackEvents returns events newest first; reverse them into chronological order.
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
+
+// ListStacksWithTags returns every stack in the account and region that carries all of tags.
+func (c CloudFormation) ListStacksWithTags(tags map[string]string) ([]StackDescription, error) {
+	var matches []StackDescription
+	var nextToken *string
+	for {
+		out, err := c.client.DescribeStacks(&sdkcloudformation.DescribeStacksInput{NextToken: nextToken})
+		if err != nil {
+			return nil, fmt.Errorf("list stacks: %w", err)
+		}
+		for _, s := range out.Stacks {
+			if !hasAllTags(s.Tags, tags) {
+				continue
+			}
+			matches = append(matches, *toStackDescription(s))
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	return matches, nil
+}
+
+func toStackDescription(s *sdkcloudformation.Stack) *StackDescription {
+	return &StackDescription{
+		StackID:     s.StackId,
+		StackName:   s.StackName,
+		StackStatus: s.StackStatus,
+		Outputs:     s.Outputs,
+		Tags:        s.Tags,
+	}
+}
+
+func hasAllTags(stackTags []*sdkcloudformation.Tag, want map[string]string) bool {
+	got := make(map[string]string, len(stackTags))
+	for _, t := range stackTags {
+		got[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// descriptionCommentPattern matches a line comment directly preceding a top-level resource
+// declaration in a rendered template, e.g. "  # The application load balancer".
+var descriptionCommentPattern = regexp.MustCompile(`^\s*#\s*(.+?)\s*$`)
+
+// resourceKeyPattern matches a top-level key in the Resources section, e.g. "  ALB:".
+var resourceKeyPattern = regexp.MustCompile(`^\s{2}([A-Za-z0-9]+):\s*$`)
+
+// ParseTemplateDescriptions scans a rendered CloudFormation template for the human-readable
+// comment directly preceding each top-level resource in its Resources section and returns a
+// map from the resource's logical ID to that comment. Resources with no preceding comment are
+// omitted: Copilot only renders a dedicated progress line for resources it has a description for.
+func ParseTemplateDescriptions(body string) (map[string]string, error) {
+	descriptions := make(map[string]string)
+	inResources := false
+	pending := ""
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "Resources:") {
+			inResources = true
+			continue
+		}
+		if !inResources {
+			continue
+		}
+		if len(line) > 0 && line[0] != ' ' && line[0] != '\t' {
+			break // Left the Resources section.
+		}
+		if m := descriptionCommentPattern.FindStringSubmatch(line); m != nil {
+			pending = m[1]
+			continue
+		}
+		if m := resourceKeyPattern.FindStringSubmatch(line); m != nil {
+			if pending != "" {
+				descriptions[m[1]] = pending
+			}
+		}
+		pending = ""
+	}
+	return descriptions, nil
+}
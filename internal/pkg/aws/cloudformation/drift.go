@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// DetectStackDrift starts an asynchronous drift detection operation on stackName and returns
+// the detection ID used to poll DescribeStackDriftDetectionStatus for its result.
+func (c CloudFormation) DetectStackDrift(stackName string) (string, error) {
+	out, err := c.client.DetectStackDrift(&sdkcloudformation.DetectStackDriftInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("detect stack drift for %s: %w", stackName, err)
+	}
+	return aws.StringValue(out.StackDriftDetectionId), nil
+}
+
+// DescribeStackDriftDetectionStatus returns the status of a drift detection operation started
+// by DetectStackDrift.
+func (c CloudFormation) DescribeStackDriftDetectionStatus(detectionID string) (*sdkcloudformation.DescribeStackDriftDetectionStatusOutput, error) {
+	out, err := c.client.DescribeStackDriftDetectionStatus(&sdkcloudformation.DescribeStackDriftDetectionStatusInput{
+		StackDriftDetectionId: aws.String(detectionID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe stack drift detection status %s: %w", detectionID, err)
+	}
+	return out, nil
+}
+
+// DescribeStackResourceDrifts returns the drift status of every resource CloudFormation was
+// able to check the last time drift detection ran against stackName.
+func (c CloudFormation) DescribeStackResourceDrifts(stackName string) ([]*sdkcloudformation.StackResourceDrift, error) {
+	var drifts []*sdkcloudformation.StackResourceDrift
+	var nextToken *string
+	for {
+		out, err := c.client.DescribeStackResourceDrifts(&sdkcloudformation.DescribeStackResourceDriftsInput{
+			StackName: aws.String(stackName),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("describe stack resource drifts for %s: %w", stackName, err)
+		}
+		drifts = append(drifts, out.StackResourceDrifts...)
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	return drifts, nil
+}
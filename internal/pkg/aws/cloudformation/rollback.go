@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ContinueUpdateRollback resumes a rollback on a stack stuck in UPDATE_ROLLBACK_FAILED,
+// optionally skipping the logical IDs in skipResources that CloudFormation can't roll back on
+// its own.
+func (c CloudFormation) ContinueUpdateRollback(stackName string, skipResources []string) error {
+	in := &sdkcloudformation.ContinueUpdateRollbackInput{
+		StackName: aws.String(stackName),
+	}
+	if len(skipResources) > 0 {
+		in.ResourcesToSkip = aws.StringSlice(skipResources)
+	}
+	if _, err := c.client.ContinueUpdateRollback(in); err != nil {
+		return fmt.Errorf("continue update rollback for stack %s: %w", stackName, err)
+	}
+	return nil
+}
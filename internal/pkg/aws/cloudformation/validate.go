@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ValidateTemplate validates an inline template body against the CloudFormation ValidateTemplate
+// API.
+func (c CloudFormation) ValidateTemplate(templateBody string) (*sdkcloudformation.ValidateTemplateOutput, error) {
+	out, err := c.client.ValidateTemplate(&sdkcloudformation.ValidateTemplateInput{
+		TemplateBody: aws.String(templateBody),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("validate template: %w", err)
+	}
+	return out, nil
+}
+
+// ValidateTemplateURL validates a template hosted at templateURL against the CloudFormation
+// ValidateTemplate API; it's used instead of ValidateTemplate once the rendered body is too
+// large to send inline.
+func (c CloudFormation) ValidateTemplateURL(templateURL string) (*sdkcloudformation.ValidateTemplateOutput, error) {
+	out, err := c.client.ValidateTemplate(&sdkcloudformation.ValidateTemplateInput{
+		TemplateURL: aws.String(templateURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("validate template at %s: %w", templateURL, err)
+	}
+	return out, nil
+}
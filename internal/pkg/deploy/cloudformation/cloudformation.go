@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -41,6 +42,7 @@ type cfnClient interface {
 	// Methods augmented by the aws wrapper struct.
 	Create(*cloudformation.Stack) (string, error)
 	CreateAndWait(*cloudformation.Stack) error
+	CreateChangeSet(stack *cloudformation.Stack, changeSetType string) (string, error)
 	WaitForCreate(ctx context.Context, stackName string) error
 	Update(*cloudformation.Stack) error
 	UpdateAndWait(*cloudformation.Stack) error
@@ -54,10 +56,16 @@ type cfnClient interface {
 	TemplateBodyFromChangeSet(changeSetID, stackName string) (string, error)
 	Events(stackName string) ([]cloudformation.StackEvent, error)
 	ListStacksWithTags(tags map[string]string) ([]cloudformation.StackDescription, error)
-	ErrorEvents(stackName string) ([]cloudformation.StackEvent, error)
+	DetectStackDrift(stackName string) (string, error)
+	DescribeStackDriftDetectionStatus(detectionID string) (*sdkcloudformation.DescribeStackDriftDetectionStatusOutput, error)
+	DescribeStackResourceDrifts(stackName string) ([]*sdkcloudformation.StackResourceDrift, error)
+	ContinueUpdateRollback(stackName string, skipResources []string) error
+	ValidateTemplate(templateBody string) (*sdkcloudformation.ValidateTemplateOutput, error)
+	ValidateTemplateURL(templateURL string) (*sdkcloudformation.ValidateTemplateOutput, error)
 
 	// Methods vended by the aws sdk struct.
 	DescribeStackEvents(*sdkcloudformation.DescribeStackEventsInput) (*sdkcloudformation.DescribeStackEventsOutput, error)
+	DescribeStackResources(*sdkcloudformation.DescribeStackResourcesInput) (*sdkcloudformation.DescribeStackResourcesOutput, error)
 }
 
 type stackSetClient interface {
@@ -75,6 +83,26 @@ type CloudFormation struct {
 	regionalClient func(region string) cfnClient
 	appStackSet    stackSetClient
 	box            packd.Box
+
+	// s3Uploader and artifactBucket are used to offload large template bodies that would
+	// otherwise exceed CloudFormation's inline request size limit. Both are unset by default;
+	// use WithLargeTemplateSupport to enable the fallback.
+	s3Uploader     s3Uploader
+	artifactBucket string
+}
+
+// s3Uploader uploads a template body to an object store and returns its URL.
+type s3Uploader interface {
+	Upload(bucket, key string, body io.Reader) (url string, err error)
+}
+
+// WithLargeTemplateSupport returns a copy of cf configured to upload templates that exceed
+// CloudFormation's 51,200-byte inline request limit to bucket via uploader, passing the
+// resulting TemplateURL to CloudFormation instead of the raw template body.
+func (cf CloudFormation) WithLargeTemplateSupport(bucket string, uploader s3Uploader) CloudFormation {
+	cf.artifactBucket = bucket
+	cf.s3Uploader = uploader
+	return cf
 }
 
 // New returns a configured CloudFormation client.
@@ -91,28 +119,28 @@ func New(sess *session.Session) CloudFormation {
 	}
 }
 
-// errorEvents returns the list of status reasons of failed resource events
-func (cf CloudFormation) errorEvents(conf StackConfiguration) ([]string, error) {
-	events, err := cf.cfnClient.ErrorEvents(conf.StackName())
-	if err != nil {
-		return nil, err
-	}
-	var reasons []string
-	for _, event := range events {
-		// CFN error messages end with a '. (Service' and only the first sentence is useful, the rest is error codes.
-		reasons = append(reasons, strings.Split(aws.StringValue(event.ResourceStatusReason), ". (Service")[0])
-	}
-	return reasons, nil
-}
-
 type renderStackChangesInput struct {
 	w                progress.FileWriter
 	stackName        string
 	stackDescription string
 	createChangeSet  func() (string, error)
+
+	// conf and validate are used to run client-side template validation before the change
+	// set is created. conf is only required when validate is true.
+	conf     StackConfiguration
+	validate bool
 }
 
 func (cf CloudFormation) renderStackChanges(in renderStackChangesInput) error {
+	if in.validate {
+		result, err := cf.Validate(in.conf)
+		if err != nil {
+			return err
+		}
+		if len(result.Diagnostics) > 0 {
+			return &ErrTemplateInvalid{StackName: in.stackName, Diagnostics: result.Diagnostics}
+		}
+	}
 	changeSetID, err := in.createChangeSet()
 	if err != nil {
 		return err
@@ -214,18 +242,47 @@ func (cf CloudFormation) errOnFailedStack(stackName string) error {
 		return err
 	}
 	status := aws.StringValue(stack.StackStatus)
+	if status == sdkcloudformation.StackStatusUpdateRollbackFailed {
+		resources, rerr := cf.updateFailedResources(stackName)
+		if rerr == nil {
+			return &ErrStackNeedsRollbackContinuation{StackName: stackName, Resources: resources}
+		}
+	}
 	if cloudformation.StackStatus(status).Failure() {
-		return fmt.Errorf("stack %s did not complete successfully and exited with status %s", stackName, status)
+		failures, ferr := cf.stackFailures(stackName)
+		if ferr != nil || len(failures) == 0 {
+			return fmt.Errorf("stack %s did not complete successfully and exited with status %s", stackName, status)
+		}
+		return &ErrStackFailed{StackName: stackName, Status: status, Failures: failures}
 	}
 	return nil
 }
 
-func toStack(config StackConfiguration) (*cloudformation.Stack, error) {
+// maxInlineTemplateBodyBytes is the largest template body CloudFormation accepts as
+// TemplateBody on CreateStack/UpdateStack/CreateChangeSet; beyond this it must be hosted
+// in S3 and referenced with TemplateURL instead.
+const maxInlineTemplateBodyBytes = 51200
+
+// needsLargeTemplateUpload reports whether a template body of bodyLen bytes must be uploaded
+// to S3 rather than passed inline, given whether an s3Uploader is configured.
+func needsLargeTemplateUpload(bodyLen int, hasUploader bool) bool {
+	return bodyLen > maxInlineTemplateBodyBytes && hasUploader
+}
+
+func (cf CloudFormation) toStack(config StackConfiguration) (*cloudformation.Stack, error) {
 	template, err := config.Template()
 	if err != nil {
 		return nil, err
 	}
 	stack := cloudformation.NewStack(config.StackName(), template)
+	if needsLargeTemplateUpload(len(template), cf.s3Uploader != nil) {
+		url, err := cf.uploadTemplate(config.StackName(), template)
+		if err != nil {
+			return nil, fmt.Errorf("upload large template for stack %s: %w", config.StackName(), err)
+		}
+		stack.Template = ""
+		stack.TemplateURL = url
+	}
 	stack.Parameters, err = config.Parameters()
 	if err != nil {
 		return nil, err
@@ -234,6 +291,54 @@ func toStack(config StackConfiguration) (*cloudformation.Stack, error) {
 	return stack, nil
 }
 
+// uploadTemplate uploads body to the configured artifact bucket and returns the object's URL
+// for use as a CloudFormation TemplateURL.
+func (cf CloudFormation) uploadTemplate(stackName, body string) (string, error) {
+	key := fmt.Sprintf("manual/templates/%s.stack.yml", stackName)
+	return cf.s3Uploader.Upload(cf.artifactBucket, key, strings.NewReader(body))
+}
+
+// createChangeSetFor renders conf into a Stack, uploading its template to S3 first if it's too
+// large to send inline, and creates a change set of changeSetType ("CREATE" or "UPDATE")
+// against it.
+func (cf CloudFormation) createChangeSetFor(conf StackConfiguration, changeSetType string) (string, error) {
+	stack, err := cf.toStack(conf)
+	if err != nil {
+		return "", err
+	}
+	return cf.cfnClient.CreateChangeSet(stack, changeSetType)
+}
+
+// CreateAndRender creates conf's stack via a change set and renders its progress on w until the
+// stack finishes creating.
+func (cf CloudFormation) CreateAndRender(w progress.FileWriter, conf StackConfiguration, stackDescription string, validate bool) error {
+	return cf.renderStackChanges(renderStackChangesInput{
+		w:                w,
+		stackName:        conf.StackName(),
+		stackDescription: stackDescription,
+		createChangeSet: func() (string, error) {
+			return cf.createChangeSetFor(conf, sdkcloudformation.ChangeSetTypeCreate)
+		},
+		conf:     conf,
+		validate: validate,
+	})
+}
+
+// UpdateAndRender updates conf's stack via a change set and renders its progress on w until the
+// stack finishes updating.
+func (cf CloudFormation) UpdateAndRender(w progress.FileWriter, conf StackConfiguration, stackDescription string, validate bool) error {
+	return cf.renderStackChanges(renderStackChangesInput{
+		w:                w,
+		stackName:        conf.StackName(),
+		stackDescription: stackDescription,
+		createChangeSet: func() (string, error) {
+			return cf.createChangeSetFor(conf, sdkcloudformation.ChangeSetTypeUpdate)
+		},
+		conf:     conf,
+		validate: validate,
+	})
+}
+
 func toMap(tags []*sdkcloudformation.Tag) map[string]string {
 	m := make(map[string]string)
 	for _, t := range tags {
@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import "testing"
+
+func TestNeedsLargeTemplateUpload(t *testing.T) {
+	testCases := map[string]struct {
+		bodyLen     int
+		hasUploader bool
+		want        bool
+	}{
+		"small body never needs upload, even with an uploader configured": {
+			bodyLen:     1024,
+			hasUploader: true,
+			want:        false,
+		},
+		"large body needs upload when an uploader is configured": {
+			bodyLen:     maxInlineTemplateBodyBytes + 1,
+			hasUploader: true,
+			want:        true,
+		},
+		"large body without an uploader is left inline": {
+			bodyLen:     maxInlineTemplateBodyBytes + 1,
+			hasUploader: false,
+			want:        false,
+		},
+		"body exactly at the limit stays inline": {
+			bodyLen:     maxInlineTemplateBodyBytes,
+			hasUploader: true,
+			want:        false,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := needsLargeTemplateUpload(tc.bodyLen, tc.hasUploader); got != tc.want {
+				t.Errorf("needsLargeTemplateUpload(%d, %t) = %t, want %t", tc.bodyLen, tc.hasUploader, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseStackNameFromARN(t *testing.T) {
+	arn := "arn:aws:cloudformation:us-west-2:123456789012:stack/my-nested-stack/d0a825a0-e4cd-xmpl-b9fb-061c69e99205"
+	want := "my-nested-stack"
+	if got := parseStackNameFromARN(arn); got != want {
+		t.Errorf("parseStackNameFromARN(%q) = %q, want %q", arn, got, want)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	// driftDetectionPollInterval is how often we check on an in-progress drift detection operation.
+	driftDetectionPollInterval = 5 * time.Second
+	// driftDetectionTimeout is how long we're willing to wait for drift detection to finish.
+	driftDetectionTimeout = 10 * time.Minute
+
+	driftDetectionStatusComplete   = "DETECTION_COMPLETE"
+	driftDetectionStatusInProgress = "DETECTION_IN_PROGRESS"
+	driftDetectionStatusFailed     = "DETECTION_FAILED"
+
+	driftStatusInSync     = "IN_SYNC"
+	driftStatusModified   = "MODIFIED"
+	driftStatusDeleted    = "DELETED"
+	driftStatusNotChecked = "NOT_CHECKED"
+)
+
+// criticalResourceTypes hold state that a re-deploy would clobber (databases, caches, queues,
+// buckets): drift on one of these is worth failing loudly on so a user can choose to import the
+// live resource instead of overwriting it. Drift on any other resource type is not escalated to
+// ErrStackDrifted; it appears in the DriftReport, which is returned only when no critical
+// resource has drifted.
+var criticalResourceTypes = map[string]bool{
+	"AWS::RDS::DBInstance":               true,
+	"AWS::RDS::DBCluster":                true,
+	"AWS::DynamoDB::Table":               true,
+	"AWS::ElastiCache::CacheCluster":     true,
+	"AWS::ElastiCache::ReplicationGroup": true,
+	"AWS::S3::Bucket":                    true,
+	"AWS::EFS::FileSystem":               true,
+	"AWS::SQS::Queue":                    true,
+}
+
+// isCriticalResourceType reports whether resourceType holds state that makes its drift worth
+// escalating to ErrStackDrifted rather than just surfacing in the DriftReport.
+func isCriticalResourceType(resourceType string) bool {
+	return criticalResourceTypes[resourceType]
+}
+
+// PropertyDifference is a single field that differs between the stack's template and the live resource.
+type PropertyDifference struct {
+	PropertyPath   string
+	ExpectedValue  string
+	ActualValue    string
+	DifferenceType string
+}
+
+// ResourceDrift describes the drift status of a single resource within a stack.
+type ResourceDrift struct {
+	LogicalResourceID   string
+	ResourceType        string
+	StackResourceStatus string // One of IN_SYNC, MODIFIED, DELETED, or NOT_CHECKED.
+	PropertyDifferences []PropertyDifference
+}
+
+// DriftReport is the result of detecting drift on a stack.
+type DriftReport struct {
+	StackName string
+	Resources []ResourceDrift
+}
+
+// ErrStackDrifted is returned by DetectDrift when one or more critical resources (see
+// isCriticalResourceType) in the stack have drifted (MODIFIED or DELETED) from the template
+// that last deployed them.
+type ErrStackDrifted struct {
+	StackName string
+	Resources []ResourceDrift
+}
+
+func (e *ErrStackDrifted) Error() string {
+	msg := fmt.Sprintf("stack %s has drifted from its template:", e.StackName)
+	for _, r := range e.Resources {
+		msg += fmt.Sprintf("\n  - %s (%s) is %s", r.LogicalResourceID, r.ResourceType, r.StackResourceStatus)
+	}
+	return msg
+}
+
+// DetectDrift kicks off drift detection for stackName, waits for it to finish, and returns a
+// DriftReport describing the drift status of every resource that CloudFormation tracks. Like
+// any other CloudFormation method here, exactly one of the return values is non-nil: if a
+// critical resource (see isCriticalResourceType) has drifted, DetectDrift returns a nil report
+// and an *ErrStackDrifted carrying the drifted resources instead, so callers can decide whether
+// to re-deploy, import, or ignore the drift without having to remember to check both values.
+func (cf CloudFormation) DetectDrift(stackName string) (*DriftReport, error) {
+	detectionID, err := cf.cfnClient.DetectStackDrift(stackName)
+	if err != nil {
+		return nil, fmt.Errorf("detect drift for stack %s: %w", stackName, err)
+	}
+
+	deadline := time.Now().Add(driftDetectionTimeout)
+poll:
+	for {
+		out, err := cf.cfnClient.DescribeStackDriftDetectionStatus(detectionID)
+		if err != nil {
+			return nil, fmt.Errorf("describe stack drift detection status for %s: %w", stackName, err)
+		}
+		switch status := aws.StringValue(out.DetectionStatus); status {
+		case driftDetectionStatusComplete:
+			break poll
+		case driftDetectionStatusFailed:
+			return nil, fmt.Errorf("drift detection failed for stack %s: %s", stackName, aws.StringValue(out.DetectionStatusReason))
+		case driftDetectionStatusInProgress:
+			// Still running; fall through to the deadline/sleep check below and poll again.
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for drift detection to complete for stack %s", stackName)
+		}
+		time.Sleep(driftDetectionPollInterval)
+	}
+
+	driftedResources, err := cf.cfnClient.DescribeStackResourceDrifts(stackName)
+	if err != nil {
+		return nil, fmt.Errorf("describe stack resource drifts for %s: %w", stackName, err)
+	}
+
+	report := &DriftReport{StackName: stackName}
+	var critical []ResourceDrift
+	for _, d := range driftedResources {
+		status := aws.StringValue(d.StackResourceDriftStatus)
+		resource := ResourceDrift{
+			LogicalResourceID:   aws.StringValue(d.LogicalResourceId),
+			ResourceType:        aws.StringValue(d.ResourceType),
+			StackResourceStatus: status,
+		}
+		for _, diff := range d.PropertyDifferences {
+			resource.PropertyDifferences = append(resource.PropertyDifferences, PropertyDifference{
+				PropertyPath:   aws.StringValue(diff.PropertyPath),
+				ExpectedValue:  aws.StringValue(diff.ExpectedValue),
+				ActualValue:    aws.StringValue(diff.ActualValue),
+				DifferenceType: aws.StringValue(diff.DifferenceType),
+			})
+		}
+		report.Resources = append(report.Resources, resource)
+		if isResourceDrifted(status) && isCriticalResourceType(resource.ResourceType) {
+			critical = append(critical, resource)
+		}
+	}
+
+	if len(critical) > 0 {
+		return nil, &ErrStackDrifted{StackName: stackName, Resources: critical}
+	}
+	return report, nil
+}
+
+// isResourceDrifted reports whether a StackResourceDriftStatus represents actual drift.
+// NOT_CHECKED is excluded: CloudFormation returns it for resource types drift detection
+// doesn't support (nested stacks, many custom/managed resources), and it's common enough in
+// Copilot's own templates that treating it as drift would make every status check noisy.
+func isResourceDrifted(status string) bool {
+	return status == driftStatusModified || status == driftStatusDeleted
+}
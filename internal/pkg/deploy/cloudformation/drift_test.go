@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import "testing"
+
+func TestIsResourceDrifted(t *testing.T) {
+	testCases := map[string]struct {
+		status string
+		want   bool
+	}{
+		"in sync is not drifted":             {status: driftStatusInSync, want: false},
+		"not checked is not drifted":         {status: driftStatusNotChecked, want: false},
+		"modified is drifted":                {status: driftStatusModified, want: true},
+		"deleted is drifted":                 {status: driftStatusDeleted, want: true},
+		"unrecognized status is not drifted": {status: "SOMETHING_NEW", want: false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := isResourceDrifted(tc.status); got != tc.want {
+				t.Errorf("isResourceDrifted(%q) = %t, want %t", tc.status, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsCriticalResourceType(t *testing.T) {
+	testCases := map[string]struct {
+		resourceType string
+		want         bool
+	}{
+		"rds instance is critical":    {resourceType: "AWS::RDS::DBInstance", want: true},
+		"dynamodb table is critical":  {resourceType: "AWS::DynamoDB::Table", want: true},
+		"ecs service is not critical": {resourceType: "AWS::ECS::Service", want: false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := isCriticalResourceType(tc.resourceType); got != tc.want {
+				t.Errorf("isCriticalResourceType(%q) = %t, want %t", tc.resourceType, got, tc.want)
+			}
+		})
+	}
+}
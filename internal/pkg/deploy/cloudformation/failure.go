@@ -0,0 +1,195 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// RemediationHint points a user at the likely cause of a stack failure and how to fix it.
+type RemediationHint struct {
+	Cause        string
+	DocLink      string
+	SuggestedFix string
+	Retriable    bool // Whether simply re-running the deploy is likely to succeed.
+}
+
+// FailureClassifier looks at a failed resource event's status reason and resource type and,
+// if it recognizes the failure, returns a RemediationHint for it.
+type FailureClassifier interface {
+	Classify(reason, resourceType string) (RemediationHint, bool)
+}
+
+// regexClassifier is a FailureClassifier that matches on the status reason and, optionally,
+// the resource type using regular expressions.
+type regexClassifier struct {
+	reason       *regexp.Regexp
+	resourceType *regexp.Regexp
+	hint         RemediationHint
+}
+
+// Classify implements FailureClassifier.
+func (c regexClassifier) Classify(reason, resourceType string) (RemediationHint, bool) {
+	if c.reason != nil && !c.reason.MatchString(reason) {
+		return RemediationHint{}, false
+	}
+	if c.resourceType != nil && !c.resourceType.MatchString(resourceType) {
+		return RemediationHint{}, false
+	}
+	return c.hint, true
+}
+
+// nestedStackFailureReason matches the generic message CloudFormation puts on a parent stack
+// when a nested stack it owns failed to create; the useful reason is on the nested stack itself.
+var nestedStackFailureReason = regexp.MustCompile(`(?i)Embedded stack .* was not successfully created`)
+
+// defaultFailureClassifiers are tried, in order, against every failed resource event.
+var defaultFailureClassifiers = []FailureClassifier{
+	regexClassifier{
+		reason: regexp.MustCompile(`(?i)is not authorized to perform`),
+		hint: RemediationHint{
+			Cause:        "an IAM role or policy used by this stack hasn't propagated yet",
+			DocLink:      "https://docs.aws.amazon.com/IAM/latest/UserGuide/troubleshoot_general.html#troubleshoot_general_eventual-consistency",
+			SuggestedFix: "wait a minute for IAM to become consistent and re-run the deploy",
+			Retriable:    true,
+		},
+	},
+	regexClassifier{
+		reason:       regexp.MustCompile(`RESOURCE_INITIALIZATION_ERROR`),
+		resourceType: regexp.MustCompile(`^AWS::ECS::Service$`),
+		hint: RemediationHint{
+			Cause:        "the ECS task failed to start, often due to a bad container image or a missing secret or environment variable",
+			DocLink:      "https://docs.aws.amazon.com/AmazonECS/latest/developerguide/stopped-task-errors.html",
+			SuggestedFix: "check the service's CloudWatch logs for the failing container and fix its configuration",
+			Retriable:    false,
+		},
+	},
+	regexClassifier{
+		reason:       regexp.MustCompile(`(?i)already exists`),
+		resourceType: regexp.MustCompile(`^AWS::ElasticLoadBalancingV2::TargetGroup$`),
+		hint: RemediationHint{
+			Cause:        "a target group with the same name already exists in this account and region",
+			DocLink:      "https://docs.aws.amazon.com/elasticloadbalancing/latest/application/application-load-balancer-limits.html",
+			SuggestedFix: "delete the conflicting target group or rename the service so Copilot generates a different name",
+			Retriable:    false,
+		},
+	},
+	regexClassifier{
+		reason: regexp.MustCompile(`(?i)(limit|quota) exceeded|LimitExceeded`),
+		hint: RemediationHint{
+			Cause:        "an AWS account quota was exceeded",
+			DocLink:      "https://docs.aws.amazon.com/servicequotas/latest/userguide/intro.html",
+			SuggestedFix: "request a quota increase in the Service Quotas console and re-run the deploy",
+			Retriable:    true,
+		},
+	},
+}
+
+// classify returns the first matching RemediationHint for a failed resource event, or nil if
+// none of the default classifiers recognize it.
+func classify(reason, resourceType string) *RemediationHint {
+	for _, c := range defaultFailureClassifiers {
+		if hint, ok := c.Classify(reason, resourceType); ok {
+			return &hint
+		}
+	}
+	return nil
+}
+
+// StackFailure is a single failed resource event, enriched with a remediation hint when one
+// of the known FailureClassifiers recognizes it.
+type StackFailure struct {
+	LogicalResourceID string
+	ResourceType      string
+	Reason            string
+	Hint              *RemediationHint
+
+	// physicalResourceID is kept unexported: it's only used internally to resolve nested
+	// stack failures and isn't a stable identifier worth exposing to callers.
+	physicalResourceID string
+}
+
+// ErrStackFailed is returned by errOnFailedStack when a stack ends in a failure status; it
+// carries every failed resource event so the root cause isn't lost behind a generic
+// "embedded stack was not successfully created" message.
+type ErrStackFailed struct {
+	StackName string
+	Status    string
+	Failures  []StackFailure
+}
+
+func (e *ErrStackFailed) Error() string {
+	msg := fmt.Sprintf("stack %s did not complete successfully and exited with status %s:", e.StackName, e.Status)
+	for _, f := range e.Failures {
+		msg += fmt.Sprintf("\n  - %s (%s): %s", f.LogicalResourceID, f.ResourceType, f.Reason)
+		if f.Hint != nil {
+			msg += fmt.Sprintf("\n    cause: %s\n    fix: %s", f.Hint.Cause, f.Hint.SuggestedFix)
+		}
+	}
+	return msg
+}
+
+// stackFailures paginates through a stack's events, classifies every failed resource event,
+// and resolves generic nested-stack failures to the underlying resource failure that caused
+// them.
+func (cf CloudFormation) stackFailures(stackName string) ([]StackFailure, error) {
+	failures, err := cf.rawStackFailures(stackName)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []StackFailure
+	for _, f := range failures {
+		if f.ResourceType == "AWS::CloudFormation::Stack" && nestedStackFailureReason.MatchString(f.Reason) && f.physicalResourceID != "" {
+			nested, err := cf.rawStackFailures(parseStackNameFromARN(f.physicalResourceID))
+			if err == nil && len(nested) > 0 {
+				resolved = append(resolved, nested...)
+				continue
+			}
+		}
+		resolved = append(resolved, f)
+	}
+	return resolved, nil
+}
+
+// rawStackFailures paginates through DescribeStackEvents for a single stack and returns every
+// event whose status ends in "_FAILED", classified but not correlated across nested stacks.
+func (cf CloudFormation) rawStackFailures(stackName string) ([]StackFailure, error) {
+	var failures []StackFailure
+	var nextToken *string
+	for {
+		out, err := cf.cfnClient.DescribeStackEvents(&sdkcloudformation.DescribeStackEventsInput{
+			StackName: aws.String(stackName),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range out.StackEvents {
+			if !strings.HasSuffix(aws.StringValue(event.ResourceStatus), "_FAILED") {
+				continue
+			}
+			// CFN error messages end with a '. (Service' and only the first sentence is useful, the rest is error codes.
+			reason := strings.Split(aws.StringValue(event.ResourceStatusReason), ". (Service")[0]
+			resourceType := aws.StringValue(event.ResourceType)
+			failures = append(failures, StackFailure{
+				LogicalResourceID:  aws.StringValue(event.LogicalResourceId),
+				ResourceType:       resourceType,
+				Reason:             reason,
+				Hint:               classify(reason, resourceType),
+				physicalResourceID: aws.StringValue(event.PhysicalResourceId),
+			})
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	return failures, nil
+}
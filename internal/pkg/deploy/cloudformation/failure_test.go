@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := map[string]struct {
+		reason       string
+		resourceType string
+		wantCause    string // Substring expected in the matched hint's Cause, "" if no match expected.
+	}{
+		"IAM propagation": {
+			reason:       "User: arn:aws:iam::123456789012:user/foo is not authorized to perform: iam:PassRole",
+			resourceType: "AWS::ECS::Service",
+			wantCause:    "IAM",
+		},
+		"ECS resource initialization error": {
+			reason:       "RESOURCE_INITIALIZATION_ERROR: unable to pull secrets or registry auth",
+			resourceType: "AWS::ECS::Service",
+			wantCause:    "ECS task failed",
+		},
+		"ECS resource initialization error on a different resource type doesn't match": {
+			reason:       "RESOURCE_INITIALIZATION_ERROR",
+			resourceType: "AWS::ECS::TaskDefinition",
+			wantCause:    "",
+		},
+		"target group name collision": {
+			reason:       "Target group already exists",
+			resourceType: "AWS::ElasticLoadBalancingV2::TargetGroup",
+			wantCause:    "target group",
+		},
+		"quota exceeded": {
+			reason:       "Rate exceeded: LimitExceeded",
+			resourceType: "AWS::EC2::VPC",
+			wantCause:    "quota",
+		},
+		"unrecognized failure": {
+			reason:       "Some brand new error CloudFormation has never returned before",
+			resourceType: "AWS::S3::Bucket",
+			wantCause:    "",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hint := classify(tc.reason, tc.resourceType)
+			if tc.wantCause == "" {
+				if hint != nil {
+					t.Errorf("classify() = %+v, want nil", hint)
+				}
+				return
+			}
+			if hint == nil {
+				t.Fatalf("classify() = nil, want a hint containing %q", tc.wantCause)
+			}
+			if !strings.Contains(hint.Cause, tc.wantCause) {
+				t.Errorf("hint.Cause = %q, want it to contain %q", hint.Cause, tc.wantCause)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/copilot-cli/internal/pkg/term/progress"
+)
+
+// Outputs returns the stack's CloudFormation outputs keyed by their OutputKey.
+func (cf CloudFormation) Outputs(stackName string) (map[string]string, error) {
+	stack, err := cf.cfnClient.Describe(stackName)
+	if err != nil {
+		return nil, fmt.Errorf("describe stack %s: %w", stackName, err)
+	}
+	outputs := make(map[string]string, len(stack.Outputs))
+	for _, o := range stack.Outputs {
+		outputs[aws.StringValue(o.OutputKey)] = aws.StringValue(o.OutputValue)
+	}
+	return outputs, nil
+}
+
+// WaitAndOutputs renders the stack's changes with renderStackChanges and, once the stack
+// completes successfully, returns its outputs. If out is non-nil, the outputs are also streamed
+// to it (e.g. so a deploy command can write them to a .env file with ExportDotenv) without a
+// second DescribeStacks round-trip.
+func (cf CloudFormation) WaitAndOutputs(in renderStackChangesInput, out progress.OutputWriter) (map[string]string, error) {
+	if err := cf.renderStackChanges(in); err != nil {
+		return nil, err
+	}
+	outputs, err := cf.Outputs(in.stackName)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil {
+		if err := out.WriteOutputs(outputs); err != nil {
+			return nil, fmt.Errorf("write outputs for stack %s: %w", in.stackName, err)
+		}
+	}
+	return outputs, nil
+}
+
+// ExportDotenv writes outputs to w as a dotenv file, converting each CamelCase output key
+// (e.g. "Port5432TcpAddr") to an UPPER_SNAKE_CASE env var name (e.g. "PORT_5432_TCP_ADDR").
+func ExportDotenv(outputs map[string]string, w io.Writer) error {
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", camelToUpperSnake(k), outputs[k]); err != nil {
+			return fmt.Errorf("write dotenv entry for %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// camelToUpperSnake converts a CamelCase identifier to UPPER_SNAKE_CASE, inserting an underscore
+// at each lower-to-upper, digit-to-upper or letter-to-digit boundary, and at an upper-to-upper boundary where the
+// second letter starts a new word (e.g. "DBHost" -> "DB_HOST"), so a leading acronym doesn't
+// collapse into the word that follows it.
+func camelToUpperSnake(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+				b.WriteByte('_')
+			case unicode.IsDigit(r) && unicode.IsLetter(prev):
+				b.WriteByte('_')
+			case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCamelToUpperSnake(t *testing.T) {
+	testCases := map[string]struct {
+		in   string
+		want string
+	}{
+		"simple camel case":         {in: "BucketName", want: "BUCKET_NAME"},
+		"digit then upper boundary": {in: "Port5432TcpAddr", want: "PORT_5432_TCP_ADDR"},
+		"already single word":       {in: "Endpoint", want: "ENDPOINT"},
+		"leading acronym splits":    {in: "DBHost", want: "DB_HOST"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := camelToUpperSnake(tc.in); got != tc.want {
+				t.Errorf("camelToUpperSnake(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExportDotenv(t *testing.T) {
+	outputs := map[string]string{
+		"Port5432TcpAddr": "10.0.0.1",
+		"BucketName":      "my-bucket",
+	}
+	var buf bytes.Buffer
+	if err := ExportDotenv(outputs, &buf); err != nil {
+		t.Fatalf("ExportDotenv returned error: %v", err)
+	}
+	want := "BUCKET_NAME=my-bucket\nPORT_5432_TCP_ADDR=10.0.0.1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ExportDotenv wrote %q, want %q", got, want)
+	}
+}
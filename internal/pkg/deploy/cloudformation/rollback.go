@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// ErrStackNeedsRollbackContinuation is returned by errOnFailedStack when a stack is stuck in
+// UPDATE_ROLLBACK_FAILED: every subsequent deploy will fail until the rollback is continued,
+// optionally skipping the resources that CloudFormation couldn't roll back on its own.
+type ErrStackNeedsRollbackContinuation struct {
+	StackName string
+	Resources []string // Logical IDs left in UPDATE_FAILED state.
+}
+
+func (e *ErrStackNeedsRollbackContinuation) Error() string {
+	return fmt.Sprintf("stack %s is stuck in UPDATE_ROLLBACK_FAILED and needs rollback continuation for resources: %s",
+		e.StackName, strings.Join(e.Resources, ", "))
+}
+
+// ContinueRollback resumes a rollback on a stack stuck in UPDATE_ROLLBACK_FAILED, optionally
+// skipping resources that CloudFormation can't roll back so the stack can reach
+// UPDATE_ROLLBACK_COMPLETE and accept further deploys.
+func (cf CloudFormation) ContinueRollback(stackName string, skipResources []string) error {
+	if err := cf.cfnClient.ContinueUpdateRollback(stackName, skipResources); err != nil {
+		return fmt.Errorf("continue rollback for stack %s: %w", stackName, err)
+	}
+	return nil
+}
+
+// updateFailedResources returns the logical IDs of every resource currently in UPDATE_FAILED
+// state, which is what ContinueUpdateRollback needs to know to skip over them. It reads the
+// stack's present resource state rather than its event history, since historical "_FAILED"
+// events (e.g. a resource that failed once at creation but has updated fine since) don't
+// reflect what CloudFormation will actually let ContinueUpdateRollback skip.
+func (cf CloudFormation) updateFailedResources(stackName string) ([]string, error) {
+	out, err := cf.cfnClient.DescribeStackResources(&sdkcloudformation.DescribeStackResourcesInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filterUpdateFailedResources(out.StackResources), nil
+}
+
+// filterUpdateFailedResources returns the logical IDs of every resource whose current status
+// is exactly UPDATE_FAILED.
+func filterUpdateFailedResources(resources []*sdkcloudformation.StackResource) []string {
+	var logicalIDs []string
+	for _, r := range resources {
+		if aws.StringValue(r.ResourceStatus) == sdkcloudformation.ResourceStatusUpdateFailed {
+			logicalIDs = append(logicalIDs, aws.StringValue(r.LogicalResourceId))
+		}
+	}
+	return logicalIDs
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+func TestFilterUpdateFailedResources(t *testing.T) {
+	testCases := map[string]struct {
+		resources []*sdkcloudformation.StackResource
+		want      []string
+	}{
+		"only resources currently in UPDATE_FAILED are returned": {
+			resources: []*sdkcloudformation.StackResource{
+				{
+					LogicalResourceId: aws.String("DBSubnetGroup"),
+					ResourceStatus:    aws.String(sdkcloudformation.ResourceStatusUpdateFailed),
+				},
+				{
+					LogicalResourceId: aws.String("Service"),
+					ResourceStatus:    aws.String(sdkcloudformation.ResourceStatusUpdateComplete),
+				},
+				{
+					LogicalResourceId: aws.String("OldResourceThatOnceFailedToCreate"),
+					ResourceStatus:    aws.String(sdkcloudformation.ResourceStatusCreateFailed),
+				},
+			},
+			want: []string{"DBSubnetGroup"},
+		},
+		"no resources in UPDATE_FAILED": {
+			resources: []*sdkcloudformation.StackResource{
+				{
+					LogicalResourceId: aws.String("Service"),
+					ResourceStatus:    aws.String(sdkcloudformation.ResourceStatusUpdateComplete),
+				},
+			},
+			want: nil,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := filterUpdateFailedResources(tc.resources)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("filterUpdateFailedResources() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
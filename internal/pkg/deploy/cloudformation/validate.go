@@ -0,0 +1,292 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	sdkcloudformation "github.com/aws/aws-sdk-go/service/cloudformation"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// Diagnostic is a single client-side validation finding.
+type Diagnostic struct {
+	Line    int // Best-effort line number within the rendered template; 0 if unknown.
+	Message string
+}
+
+// ValidationResult is the outcome of validating a stack's rendered template, both against the
+// CloudFormation ValidateTemplate API and against a handful of local, fast checks.
+type ValidationResult struct {
+	Diagnostics []Diagnostic
+}
+
+// ErrTemplateInvalid is returned by renderStackChanges when Validate reports any diagnostics,
+// so a deploy fails fast instead of waiting on a change set.
+type ErrTemplateInvalid struct {
+	StackName   string
+	Diagnostics []Diagnostic
+}
+
+func (e *ErrTemplateInvalid) Error() string {
+	msg := fmt.Sprintf("template for stack %s is invalid:", e.StackName)
+	for _, d := range e.Diagnostics {
+		if d.Line > 0 {
+			msg += fmt.Sprintf("\n  line %d: %s", d.Line, d.Message)
+			continue
+		}
+		msg += fmt.Sprintf("\n  %s", d.Message)
+	}
+	return msg
+}
+
+// subTokenPattern matches a "${Identifier}" substitution inside an Fn::Sub string. The
+// identifier may contain "." for an attribute reference (e.g. "${MyQueue.Arn}") or "::" for a
+// pseudo parameter (e.g. "${AWS::Region}").
+var subTokenPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// isPseudoParameter reports whether name is one of CloudFormation's built-in AWS:: pseudo
+// parameters (AWS::Region, AWS::AccountId, AWS::NoValue, ...), which are always valid Ref
+// targets and are never declared in a template's own Parameters or Resources sections.
+func isPseudoParameter(name string) bool {
+	return strings.HasPrefix(name, "AWS::")
+}
+
+// refUse is a single identifier referenced by !Ref, !GetAtt, or !Sub somewhere in a template,
+// along with the line it was found on for diagnostics.
+type refUse struct {
+	Name string
+	Line int
+}
+
+// declaredLogicalIDs parses root's top-level Parameters and Resources sections and returns the
+// set of logical IDs they declare.
+func declaredLogicalIDs(root *yaml.Node) map[string]bool {
+	declared := make(map[string]bool)
+	if root.Kind != yaml.MappingNode {
+		return declared
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, section := root.Content[i], root.Content[i+1]
+		if key.Value != "Parameters" && key.Value != "Resources" {
+			continue
+		}
+		if section.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(section.Content); j += 2 {
+			declared[section.Content[j].Value] = true
+		}
+	}
+	return declared
+}
+
+// scanRefs walks every node in a parsed template and returns every identifier referenced via
+// !Ref/Ref, !GetAtt/Fn::GetAtt, or !Sub/Fn::Sub, wherever in the document it appears.
+func scanRefs(node *yaml.Node) []refUse {
+	var uses []refUse
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			switch n.Tag {
+			case "!Ref":
+				uses = append(uses, refUse{Name: n.Value, Line: n.Line})
+			case "!GetAtt":
+				uses = append(uses, refUse{Name: getAttLogicalID(n.Value), Line: n.Line})
+			case "!Sub":
+				uses = append(uses, subRefs(n.Value, n.Line, nil)...)
+			}
+		case yaml.SequenceNode:
+			switch n.Tag {
+			case "!GetAtt":
+				if len(n.Content) > 0 {
+					uses = append(uses, refUse{Name: n.Content[0].Value, Line: n.Content[0].Line})
+				}
+			case "!Sub":
+				if len(n.Content) > 0 {
+					var locals map[string]bool
+					if len(n.Content) > 1 {
+						locals = mappingKeys(n.Content[1])
+					}
+					uses = append(uses, subRefs(n.Content[0].Value, n.Content[0].Line, locals)...)
+				}
+			}
+			for _, c := range n.Content {
+				walk(c)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				switch key.Value {
+				case "Ref":
+					if value.Kind == yaml.ScalarNode {
+						uses = append(uses, refUse{Name: value.Value, Line: value.Line})
+					}
+				case "Fn::GetAtt":
+					switch value.Kind {
+					case yaml.ScalarNode:
+						uses = append(uses, refUse{Name: getAttLogicalID(value.Value), Line: value.Line})
+					case yaml.SequenceNode:
+						if len(value.Content) > 0 {
+							uses = append(uses, refUse{Name: value.Content[0].Value, Line: value.Content[0].Line})
+						}
+					}
+				case "Fn::Sub":
+					switch value.Kind {
+					case yaml.ScalarNode:
+						uses = append(uses, subRefs(value.Value, value.Line, nil)...)
+					case yaml.SequenceNode:
+						if len(value.Content) > 0 {
+							var locals map[string]bool
+							if len(value.Content) > 1 {
+								locals = mappingKeys(value.Content[1])
+								walk(value.Content[1])
+							}
+							uses = append(uses, subRefs(value.Content[0].Value, value.Content[0].Line, locals)...)
+						}
+					}
+				default:
+					walk(value)
+				}
+			}
+		}
+	}
+	walk(node)
+	return uses
+}
+
+// getAttLogicalID returns the logical ID portion of a "LogicalID.Attribute" GetAtt reference.
+func getAttLogicalID(s string) string {
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+// mappingKeys returns the set of top-level keys in a mapping node, or an empty set if n isn't
+// one. It's used to find the substitution names an Fn::Sub's variable map defines locally, so
+// those names aren't flagged as undeclared when they appear in the Sub's template string.
+func mappingKeys(n *yaml.Node) map[string]bool {
+	keys := make(map[string]bool)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return keys
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys[n.Content[i].Value] = true
+	}
+	return keys
+}
+
+// subRefs extracts every "${Identifier}" token from an Fn::Sub template string s, stripping any
+// ".Attribute" suffix and skipping names locals defines or that are "${!Literal}" escapes
+// (CloudFormation's way of emitting a literal "${Literal}" in the rendered output, common in
+// user-data scripts and IAM policy documents that embed shell/JSON syntax). Pseudo parameters
+// such as "${AWS::Region}" are returned like any other name.
+func subRefs(s string, line int, locals map[string]bool) []refUse {
+	var uses []refUse
+	for _, m := range subTokenPattern.FindAllStringSubmatch(s, -1) {
+		token := m[1]
+		if strings.HasPrefix(token, "!") {
+			continue
+		}
+		name := getAttLogicalID(token)
+		if locals[name] {
+			continue
+		}
+		uses = append(uses, refUse{Name: name, Line: line})
+	}
+	return uses
+}
+
+// Validate runs the CloudFormation ValidateTemplate API against conf's rendered template, then
+// layers on a handful of local checks that are much cheaper than a full change set round-trip:
+// every declared parameter has a value, tag keys and values are within CloudFormation's length
+// limits, and every !Ref resolves to a declared parameter or resource.
+func (cf CloudFormation) Validate(conf StackConfiguration) (*ValidationResult, error) {
+	body, err := conf.Template()
+	if err != nil {
+		return nil, err
+	}
+
+	var out *sdkcloudformation.ValidateTemplateOutput
+	if needsLargeTemplateUpload(len(body), cf.s3Uploader != nil) {
+		// ValidateTemplate is subject to the same inline request-size limit as
+		// CreateStack/UpdateStack/CreateChangeSet; route large bodies through the same
+		// S3 fallback toStack uses instead of failing validation before we even try to deploy.
+		url, err := cf.uploadTemplate(conf.StackName(), body)
+		if err != nil {
+			return nil, fmt.Errorf("upload large template for stack %s: %w", conf.StackName(), err)
+		}
+		out, err = cf.cfnClient.ValidateTemplateURL(url)
+		if err != nil {
+			return nil, fmt.Errorf("validate template for stack %s: %w", conf.StackName(), err)
+		}
+	} else {
+		var err error
+		out, err = cf.cfnClient.ValidateTemplate(body)
+		if err != nil {
+			return nil, fmt.Errorf("validate template for stack %s: %w", conf.StackName(), err)
+		}
+	}
+
+	var diagnostics []Diagnostic
+
+	params, err := conf.Parameters()
+	if err != nil {
+		return nil, err
+	}
+	provided := make(map[string]bool, len(params))
+	for _, p := range params {
+		provided[aws.StringValue(p.ParameterKey)] = true
+	}
+	for _, p := range out.Parameters {
+		key := aws.StringValue(p.ParameterKey)
+		if p.DefaultValue == nil && !provided[key] {
+			diagnostics = append(diagnostics, Diagnostic{Message: fmt.Sprintf("missing value for required parameter %s", key)})
+		}
+	}
+
+	for _, t := range conf.Tags() {
+		key, value := aws.StringValue(t.Key), aws.StringValue(t.Value)
+		if len(key) > maxTagKeyLength {
+			diagnostics = append(diagnostics, Diagnostic{Message: fmt.Sprintf("tag key %q exceeds %d characters", key, maxTagKeyLength)})
+		}
+		if len(value) > maxTagValueLength {
+			diagnostics = append(diagnostics, Diagnostic{Message: fmt.Sprintf("tag value for key %q exceeds %d characters", key, maxTagValueLength)})
+		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &root); err != nil {
+		return nil, fmt.Errorf("parse cloudformation template for stack %s: %w", conf.StackName(), err)
+	}
+	doc := &root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		doc = doc.Content[0]
+	}
+
+	known := declaredLogicalIDs(doc)
+	for _, p := range out.Parameters {
+		known[aws.StringValue(p.ParameterKey)] = true
+	}
+	for _, use := range scanRefs(doc) {
+		if use.Name == "" || isPseudoParameter(use.Name) {
+			continue
+		}
+		if !known[use.Name] {
+			diagnostics = append(diagnostics, Diagnostic{Line: use.Line, Message: fmt.Sprintf("Ref to undeclared parameter or resource %q", use.Name)})
+		}
+	}
+
+	return &ValidationResult{Diagnostics: diagnostics}, nil
+}
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudformation
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseTestTemplate(t *testing.T, body string) *yaml.Node {
+	t.Helper()
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &root); err != nil {
+		t.Fatalf("parse test template: %v", err)
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0]
+	}
+	return &root
+}
+
+func TestScanRefs(t *testing.T) {
+	template := `
+Parameters:
+  AppName:
+    Type: String
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+  Queue:
+    Type: AWS::SQS::Queue
+    Properties:
+      Name: !Ref AppName
+  Subscription:
+    Type: AWS::SNS::Subscription
+    Properties:
+      Endpoint: !GetAtt Queue.Arn
+      TopicArn:
+        Fn::GetAtt: [Bucket, Arn]
+      FilterPolicy: !Sub "${Queue.Arn}-${AWS::Region}-${Undeclared}"
+`
+	doc := parseTestTemplate(t, template)
+	uses := scanRefs(doc)
+
+	got := make(map[string]bool, len(uses))
+	for _, u := range uses {
+		got[u.Name] = true
+	}
+	for _, want := range []string{"AppName", "Queue", "Bucket", "AWS::Region", "Undeclared"} {
+		if !got[want] {
+			t.Errorf("scanRefs() missing reference to %q, got %v", want, uses)
+		}
+	}
+}
+
+func TestScanRefs_SubLiteralEscape(t *testing.T) {
+	template := `
+Resources:
+  Instance:
+    Type: AWS::EC2::Instance
+    Properties:
+      UserData: !Sub "echo \"${!Literal}\" > /tmp/out; echo ${AWS::Region}"
+`
+	doc := parseTestTemplate(t, template)
+	uses := scanRefs(doc)
+
+	for _, u := range uses {
+		if u.Name == "!Literal" || u.Name == "Literal" {
+			t.Errorf("scanRefs() should not treat the %q escape as a reference, got %v", "${!Literal}", uses)
+		}
+	}
+	var sawRegion bool
+	for _, u := range uses {
+		if u.Name == "AWS::Region" {
+			sawRegion = true
+		}
+	}
+	if !sawRegion {
+		t.Errorf("scanRefs() missing reference to %q, got %v", "AWS::Region", uses)
+	}
+}
+
+func TestDeclaredLogicalIDs(t *testing.T) {
+	template := `
+Parameters:
+  AppName:
+    Type: String
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+Outputs:
+  BucketName:
+    Value: !Ref Bucket
+`
+	doc := parseTestTemplate(t, template)
+	declared := declaredLogicalIDs(doc)
+	for _, want := range []string{"AppName", "Bucket"} {
+		if !declared[want] {
+			t.Errorf("declaredLogicalIDs() missing %q, got %v", want, declared)
+		}
+	}
+	if declared["BucketName"] {
+		t.Errorf("declaredLogicalIDs() should not include Outputs, got %v", declared)
+	}
+}
+
+func TestIsPseudoParameter(t *testing.T) {
+	testCases := map[string]struct {
+		name string
+		want bool
+	}{
+		"pseudo parameter":     {name: "AWS::Region", want: true},
+		"pseudo parameter two": {name: "AWS::NoValue", want: true},
+		"declared parameter":   {name: "AppName", want: false},
+		"declared resource":    {name: "MyBucket", want: false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := isPseudoParameter(tc.name); got != tc.want {
+				t.Errorf("isPseudoParameter(%q) = %t, want %t", tc.name, got, tc.want)
+			}
+		})
+	}
+}